@@ -12,6 +12,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"unsafe"
 )
@@ -19,17 +20,20 @@ import (
 // New object file format.
 //
 //    Header struct {
-//       Magic   [...]byte   // "\x00go115ld"
-//       Flags   uint32
-//       // TODO: Fingerprint
-//       Offsets [...]uint32 // byte offset of each block below
+//       Magic              [...]byte  // "\x00go116ld"
+//       Flags              uint32
+//       ObjFileFingerprint [8]byte    // hash of this object's SymbolDefs, for Autolib verification
+//       Offsets            [...]uint32 // byte offset of each block below
 //    }
 //
 //    Strings [...]struct {
 //       Data [...]byte
 //    }
 //
-//    Autolib  [...]string // imported packages (for file loading) // TODO: add fingerprints
+//    Autolib [...]struct { // imported packages (for file loading), with the
+//       Pkg         string   // fingerprint each was compiled with, so a linker
+//       Fingerprint [8]byte  // pulling in a stale .a can detect the mismatch
+//    }
 //    PkgIndex [...]string // referenced packages by index
 //
 //    DwarfFiles [...]string
@@ -68,6 +72,12 @@ import (
 //    Data   [...]byte
 //    Pcdata [...]byte
 //
+// For most Aux types, Sym references a symbol whose Data holds
+// arbitrary-length content (e.g. DWARF bytes). For the XCOFF/AIX aux
+// types below (AuxCsect, AuxFile, AuxFunctionBounds, AuxBlock,
+// AuxExceptionTable), Sym's Data additionally begins with a small
+// fixed-width header specific to that aux type; see AuxPayload.
+//
 // string is encoded as is a uint32 length followed by a uint32 offset
 // that points to the corresponding string bytes.
 //
@@ -148,17 +158,34 @@ const (
 
 // File header.
 // TODO: probably no need to export this.
+//
+// ObjFileFingerprint is a hash of this object's SymbolDefs (names,
+// ABIs, and flags, not data bytes, so it stays stable across
+// reproducible recompiles) recorded by importers in their own Autolib
+// entry for this package; see Reader.VerifyAgainst.
 type Header struct {
-	Magic   string
-	Flags   uint32
-	Offsets [NBlk]uint32
+	Magic              string
+	Flags              uint32
+	ObjFileFingerprint [8]byte
+	Offsets            [NBlk]uint32
+
+	// old records whether Magic was oldMagic, i.e. this Header was
+	// read from an object file that predates ObjFileFingerprint and
+	// per-package Autolib fingerprints.
+	old bool
 }
 
-const Magic = "\x00go115ld"
+const Magic = "\x00go116ld"
+
+// oldMagic is the magic of the previous object file format, which had
+// no ObjFileFingerprint field. Reading it is supported so that a
+// bootstrap toolchain built from the previous release can still link.
+const oldMagic = "\x00go115ld"
 
 func (h *Header) Write(w *Writer) {
 	w.RawString(h.Magic)
 	w.Uint32(h.Flags)
+	w.Bytes(h.ObjFileFingerprint[:])
 	for _, x := range h.Offsets {
 		w.Uint32(x)
 	}
@@ -167,12 +194,21 @@ func (h *Header) Write(w *Writer) {
 func (h *Header) Read(r *Reader) error {
 	b := r.BytesAt(0, len(Magic))
 	h.Magic = string(b)
-	if h.Magic != Magic {
+	switch h.Magic {
+	case Magic:
+		h.old = false
+	case oldMagic:
+		h.old = true
+	default:
 		return errors.New("wrong magic, not a Go object file")
 	}
 	off := uint32(len(h.Magic))
 	h.Flags = r.uint32At(off)
 	off += 4
+	if !h.old {
+		copy(h.ObjFileFingerprint[:], r.BytesAt(off, len(h.ObjFileFingerprint)))
+		off += uint32(len(h.ObjFileFingerprint))
+	}
 	for i := range h.Offsets {
 		h.Offsets[i] = r.uint32At(off)
 		off += 4
@@ -181,17 +217,44 @@ func (h *Header) Read(r *Reader) error {
 }
 
 func (h *Header) Size() int {
-	return len(h.Magic) + 4 + 4*len(h.Offsets)
+	return len(h.Magic) + 4 + len(h.ObjFileFingerprint) + 4*len(h.Offsets)
+}
+
+// ComputeFingerprint computes the ObjFileFingerprint for an object
+// file defining syms. It hashes each symbol's name, ABI, and flag
+// bits, deliberately excluding Siz, Align, and Data so that the
+// fingerprint stays stable across reproducible recompiles that change
+// nothing observable to importers.
+func ComputeFingerprint(syms []Sym) [8]byte {
+	h := fnv.New64a()
+	var b [2]byte
+	for _, s := range syms {
+		io.WriteString(h, s.Name)
+		binary.LittleEndian.PutUint16(b[:], s.ABI)
+		h.Write(b[:])
+		h.Write([]byte{s.Flag})
+	}
+	var fp [8]byte
+	binary.LittleEndian.PutUint64(fp[:], h.Sum64())
+	return fp
 }
 
 // Symbol definition.
+//
+// SMClass and CsectParent are only meaningful for the XCOFF/AIX
+// backend: SMClass is the symbol's storage-mapping class (one of the
+// XMC_* constants) and CsectParent is the symbol defining the csect
+// this symbol belongs to (the zero SymRef if this symbol is itself a
+// csect). ELF-like backends ignore both.
 type Sym struct {
-	Name  string
-	ABI   uint16
-	Type  uint8
-	Flag  uint8
-	Siz   uint32
-	Align uint32
+	Name        string
+	ABI         uint16
+	Type        uint8
+	Flag        uint8
+	Siz         uint32
+	Align       uint32
+	SMClass     uint8
+	CsectParent SymRef
 }
 
 const SymABIstatic = ^uint16(0)
@@ -211,6 +274,19 @@ const (
 	SymFlagTopFrame
 )
 
+// XCOFF storage-mapping classes (SMClass), as assigned to csects and
+// the symbols that live in them.
+const (
+	XMC_PR = iota // program code
+	XMC_RO        // read-only data
+	XMC_RW        // read/write data
+	XMC_DS        // function descriptor
+	XMC_TC        // TOC entry
+	XMC_TD        // scalar data TOC entry
+	XMC_BS        // BSS (uninitialized data)
+	XMC_UA        // unclassified
+)
+
 func (s *Sym) Write(w *Writer) {
 	w.StringRef(s.Name)
 	w.Uint16(s.ABI)
@@ -218,9 +294,28 @@ func (s *Sym) Write(w *Writer) {
 	w.Uint8(s.Flag)
 	w.Uint32(s.Siz)
 	w.Uint32(s.Align)
+	w.Uint8(s.SMClass)
+	s.CsectParent.Write(w)
 }
 
-const SymSize = stringRefSize + 2 + 1 + 1 + 4 + 4
+// SetCsect sets s's XCOFF csect membership ahead of s.Write: parent is
+// the SymRef of the csect s belongs to (the zero SymRef if s is itself
+// a csect), and smc is s's storage-mapping class (one of the XMC_*
+// constants). Object writers that don't target AIX can leave these
+// fields zero.
+//
+// This is a method on Sym rather than Writer, deliberately: Writer
+// streams each block straight to its underlying bio.Writer as it is
+// produced and retains no symbol table of its own to address by
+// SymRef (the only thing it patches after the fact is the Header,
+// by seeking back to offset 0). A Writer-rooted setter would need
+// such a table, which no other part of the writing path requires.
+func (s *Sym) SetCsect(parent SymRef, smc uint8) {
+	s.CsectParent = parent
+	s.SMClass = smc
+}
+
+const SymSize = stringRefSize + 2 + 1 + 1 + 4 + 4 + 1 + 8
 
 type Sym2 [SymSize]byte
 
@@ -230,11 +325,15 @@ func (s *Sym2) Name(r *Reader) string {
 	return r.StringAt(off, len)
 }
 
-func (s *Sym2) ABI() uint16   { return binary.LittleEndian.Uint16(s[8:]) }
-func (s *Sym2) Type() uint8   { return s[10] }
-func (s *Sym2) Flag() uint8   { return s[11] }
-func (s *Sym2) Siz() uint32   { return binary.LittleEndian.Uint32(s[12:]) }
-func (s *Sym2) Align() uint32 { return binary.LittleEndian.Uint32(s[16:]) }
+func (s *Sym2) ABI() uint16    { return binary.LittleEndian.Uint16(s[8:]) }
+func (s *Sym2) Type() uint8    { return s[10] }
+func (s *Sym2) Flag() uint8    { return s[11] }
+func (s *Sym2) Siz() uint32    { return binary.LittleEndian.Uint32(s[12:]) }
+func (s *Sym2) Align() uint32  { return binary.LittleEndian.Uint32(s[16:]) }
+func (s *Sym2) SMClass() uint8 { return s[20] }
+func (s *Sym2) CsectParent() SymRef {
+	return SymRef{binary.LittleEndian.Uint32(s[21:]), binary.LittleEndian.Uint32(s[25:])}
+}
 
 func (s *Sym2) Dupok() bool         { return s.Flag()&SymFlagDupok != 0 }
 func (s *Sym2) Local() bool         { return s.Flag()&SymFlagLocal != 0 }
@@ -302,6 +401,27 @@ func (r *Reloc2) Set(off int32, size uint8, typ uint8, add int64, sym SymRef) {
 	r.SetSym(sym)
 }
 
+// XCOFF/AIX TOC relocation classes. Reloc.Type otherwise holds an
+// objabi.RelocType value; these extend that space so TOC-pointer-
+// relative addressing (typical of XCOFF on ppc64/ppc32 AIX) can be
+// expressed natively, rather than synthesised by the linker from a
+// plain PC-relative or address relocation.
+const (
+	// R_TOC resolves to the offset of Sym's TOC entry (XMC_TC) from
+	// the TOC anchor, for loading a TOC-relative address into a
+	// register (e.g. "addis/ld" off r2 on ppc64).
+	R_TOC = iota + 200
+	// R_TOCREL resolves to the offset of Sym from the TOC anchor
+	// directly, for code addressing data through the TOC without an
+	// intervening TOC entry symbol.
+	R_TOCREL
+	// R_REF adds a reference to Sym without patching any bytes; it
+	// exists only to keep Sym live and correctly ordered relative to
+	// its csect, e.g. the function descriptor's reference to its
+	// entry point.
+	R_REF
+)
+
 // Aux symbol info.
 type Aux struct {
 	Type uint8
@@ -318,6 +438,18 @@ const (
 	AuxDwarfRanges
 	AuxDwarfLines
 
+	// AuxCsect, AuxFile, AuxFunctionBounds, AuxBlock, and
+	// AuxExceptionTable carry the auxiliary symbol table entries that
+	// XCOFF-based AIX toolchains require. The referenced symbol's Data
+	// begins with the fixed-width header documented by the
+	// corresponding *Aux type below (CsectAux, FileAux, and so on),
+	// readable via Reader.AuxPayload.
+	AuxCsect
+	AuxFile
+	AuxFunctionBounds
+	AuxBlock
+	AuxExceptionTable
+
 	// TODO: more. Pcdata?
 )
 
@@ -335,6 +467,164 @@ func (a *Aux2) Sym() SymRef {
 	return SymRef{binary.LittleEndian.Uint32(a[1:]), binary.LittleEndian.Uint32(a[5:])}
 }
 
+// XCOFF/AIX auxiliary symbol table entry payloads.
+//
+// These mirror the fixed-width portion of the aux entries that an
+// XCOFF symbol table carries alongside a csect, per the corresponding
+// Aux type above. Each is written into the Data of the symbol that an
+// Aux entry of that type references, so the AIX backend of the linker
+// can read it back with Reader.AuxPayload without inventing a second
+// container for XCOFF-specific bookkeeping.
+
+// XCOFF symbol types (SymType), identifying what kind of csect a
+// CsectAux describes.
+const (
+	XTY_ER = iota // external reference
+	XTY_SD        // csect definition (section definition)
+	XTY_LD        // label definition (entry point into an XTY_SD csect)
+	XTY_CM        // common (uninitialized) csect
+)
+
+// CsectAux describes the control section (csect) a symbol belongs to:
+// its length, storage-mapping class (the XMC_* constants), required
+// alignment (log2 bytes, as XCOFF encodes it), and XCOFF symbol type
+// (the XTY_* constants).
+type CsectAux struct {
+	Length  uint64
+	Align   uint8
+	SymType uint8
+	SMClass uint8
+}
+
+func (a *CsectAux) Write(w *Writer) {
+	w.Uint64(a.Length)
+	w.Uint8(a.Align)
+	w.Uint8(a.SymType)
+	w.Uint8(a.SMClass)
+}
+
+const CsectAuxSize = 8 + 1 + 1 + 1
+
+type CsectAux2 [CsectAuxSize]byte
+
+func (a *CsectAux2) Length() uint64 { return binary.LittleEndian.Uint64(a[:]) }
+func (a *CsectAux2) Align() uint8   { return a[8] }
+func (a *CsectAux2) SymType() uint8 { return a[9] }
+func (a *CsectAux2) SMClass() uint8 { return a[10] }
+
+// FileAux describes a source file aux entry. The file name itself is
+// the Name of the referenced symbol; Lang and CPU are the XCOFF
+// x_ftype language identifier and x_cpu CPU version id.
+type FileAux struct {
+	Lang uint8
+	CPU  uint8
+}
+
+func (a *FileAux) Write(w *Writer) {
+	w.Uint8(a.Lang)
+	w.Uint8(a.CPU)
+}
+
+const FileAuxSize = 1 + 1
+
+type FileAux2 [FileAuxSize]byte
+
+func (a *FileAux2) Lang() uint8 { return a[0] }
+func (a *FileAux2) CPU() uint8  { return a[1] }
+
+// FunctionBoundsAux anchors a function's .bf/.ef pair (the symbols
+// XCOFF debuggers walk to find a function's extent) and records the
+// stack frame size and the XCOFF tag bits describing it.
+type FunctionBoundsAux struct {
+	BeginSym SymRef // .bf anchor
+	EndSym   SymRef // .ef anchor
+	FrameSiz uint32
+	TagBits  uint16
+}
+
+func (a *FunctionBoundsAux) Write(w *Writer) {
+	a.BeginSym.Write(w)
+	a.EndSym.Write(w)
+	w.Uint32(a.FrameSiz)
+	w.Uint16(a.TagBits)
+}
+
+const FunctionBoundsAuxSize = 8 + 8 + 4 + 2
+
+type FunctionBoundsAux2 [FunctionBoundsAuxSize]byte
+
+func (a *FunctionBoundsAux2) BeginSym() SymRef {
+	return SymRef{binary.LittleEndian.Uint32(a[0:]), binary.LittleEndian.Uint32(a[4:])}
+}
+func (a *FunctionBoundsAux2) EndSym() SymRef {
+	return SymRef{binary.LittleEndian.Uint32(a[8:]), binary.LittleEndian.Uint32(a[12:])}
+}
+func (a *FunctionBoundsAux2) FrameSiz() uint32 { return binary.LittleEndian.Uint32(a[16:]) }
+func (a *FunctionBoundsAux2) TagBits() uint16  { return binary.LittleEndian.Uint16(a[20:]) }
+
+// BlockAux anchors a lexical block's .bb/.eb pair, analogous to
+// FunctionBoundsAux but for nested blocks within a function.
+type BlockAux struct {
+	BeginSym SymRef // .bb anchor
+	EndSym   SymRef // .eb anchor
+}
+
+func (a *BlockAux) Write(w *Writer) {
+	a.BeginSym.Write(w)
+	a.EndSym.Write(w)
+}
+
+const BlockAuxSize = 8 + 8
+
+type BlockAux2 [BlockAuxSize]byte
+
+func (a *BlockAux2) BeginSym() SymRef {
+	return SymRef{binary.LittleEndian.Uint32(a[0:]), binary.LittleEndian.Uint32(a[4:])}
+}
+func (a *BlockAux2) EndSym() SymRef {
+	return SymRef{binary.LittleEndian.Uint32(a[8:]), binary.LittleEndian.Uint32(a[12:])}
+}
+
+// ExceptionTableAux records the offsets an XCOFF exception table entry
+// needs to catch setjmp/longjmp-style stack unwinds through a
+// function: the offset of the langauge-specific exception action and
+// the offset at which the function resumes after the longjmp.
+type ExceptionTableAux struct {
+	ActionOffset uint32
+	ResumeOffset uint32
+}
+
+func (a *ExceptionTableAux) Write(w *Writer) {
+	w.Uint32(a.ActionOffset)
+	w.Uint32(a.ResumeOffset)
+}
+
+const ExceptionTableAuxSize = 4 + 4
+
+type ExceptionTableAux2 [ExceptionTableAuxSize]byte
+
+func (a *ExceptionTableAux2) ActionOffset() uint32 { return binary.LittleEndian.Uint32(a[0:]) }
+func (a *ExceptionTableAux2) ResumeOffset() uint32 { return binary.LittleEndian.Uint32(a[4:]) }
+
+// auxPayloadSize returns the size, in bytes, of the fixed-width aux
+// payload header for the given Aux type, or 0 if that type has no
+// such header (its referenced symbol's Data is used as is).
+func auxPayloadSize(auxType uint8) int {
+	switch auxType {
+	case AuxCsect:
+		return CsectAuxSize
+	case AuxFile:
+		return FileAuxSize
+	case AuxFunctionBounds:
+		return FunctionBoundsAuxSize
+	case AuxBlock:
+		return BlockAuxSize
+	case AuxExceptionTable:
+		return ExceptionTableAuxSize
+	}
+	return 0
+}
+
 type Writer struct {
 	wr        *bio.Writer
 	stringMap map[string]uint32
@@ -362,6 +652,14 @@ func (w *Writer) StringRef(s string) {
 	w.Uint32(off)
 }
 
+// AddAutolib writes one Autolib entry: the path of an imported
+// package (which must already have been added with AddString) and the
+// ObjFileFingerprint it was compiled with.
+func (w *Writer) AddAutolib(pkg string, fp [8]byte) {
+	w.StringRef(pkg)
+	w.Bytes(fp[:])
+}
+
 func (w *Writer) RawString(s string) {
 	w.wr.WriteString(s)
 	w.off += uint32(len(s))
@@ -483,16 +781,100 @@ func (r *Reader) StringRef(off uint32) string {
 	return r.StringAt(r.uint32At(off+4), l)
 }
 
+// PkgFingerprint is one Autolib entry: an imported package and the
+// ObjFileFingerprint its object file carried when this file was
+// compiled against it.
+type PkgFingerprint struct {
+	Pkg         string
+	Fingerprint [8]byte
+}
+
+const autolibSize = stringRefSize + 8 // string ref plus fingerprint
+
+// autolibEntrySize returns the on-disk size of one Autolib entry,
+// which depends on the object file's format version: oldMagic objects
+// predate per-package fingerprints and store a bare string ref.
+func (r *Reader) autolibEntrySize() uint32 {
+	if r.h.old {
+		return stringRefSize
+	}
+	return autolibSize
+}
+
+func (r *Reader) NAutolib() int {
+	return int(r.h.Offsets[BlkAutolib+1]-r.h.Offsets[BlkAutolib]) / int(r.autolibEntrySize())
+}
+
 func (r *Reader) Autolib() []string {
-	n := (r.h.Offsets[BlkAutolib+1] - r.h.Offsets[BlkAutolib]) / stringRefSize
+	n := r.NAutolib()
 	s := make([]string, n)
+	stride := r.autolibEntrySize()
 	for i := range s {
-		off := r.h.Offsets[BlkAutolib] + uint32(i)*stringRefSize
+		off := r.h.Offsets[BlkAutolib] + uint32(i)*stride
 		s[i] = r.StringRef(off)
 	}
 	return s
 }
 
+// ErrNoFingerprint is returned by AutolibFingerprints for an object
+// file that predates per-package Autolib fingerprints (see Header.old);
+// VerifyAgainst treats it as nothing to verify and returns nil. It is
+// distinct from a verification failure: callers
+// that can't tell an old-format bootstrap object from a deliberately
+// tampered one should treat it as "nothing to verify" rather than
+// rejecting the object outright.
+var ErrNoFingerprint = errors.New("goobj2: object file has old header format with no Autolib fingerprints")
+
+// AutolibFingerprints returns the packages this object imports,
+// together with the ObjFileFingerprint each had when this object was
+// compiled. See VerifyAgainst. It returns ErrNoFingerprint if this
+// object predates per-package Autolib fingerprints (see Header.old).
+func (r *Reader) AutolibFingerprints() ([]PkgFingerprint, error) {
+	if r.h.old {
+		return nil, ErrNoFingerprint
+	}
+	n := r.NAutolib()
+	s := make([]PkgFingerprint, n)
+	for i := range s {
+		off := r.h.Offsets[BlkAutolib] + uint32(i)*autolibSize
+		s[i].Pkg = r.StringRef(off)
+		copy(s[i].Fingerprint[:], r.BytesAt(off+stringRefSize, 8))
+	}
+	return s, nil
+}
+
+// VerifyAgainst checks this object's recorded Autolib fingerprints
+// against deps, a map from package path to that package's actual
+// ObjFileFingerprint. It returns an error describing the first
+// mismatch it finds, so a linker can refuse to splice together object
+// files built against different versions of a dependency (a stale .a
+// in the build cache, for instance) rather than producing a binary
+// with mismatched assumptions about that dependency's ABI. A package
+// this object imports that has no entry in deps is not reported here;
+// that is the caller's responsibility to catch as a missing import. An
+// old-format object with no recorded fingerprints at all (ErrNoFingerprint)
+// has nothing to verify and is not an error here; bootstrap toolchains
+// that still produce such objects should not be rejected by this check.
+func (r *Reader) VerifyAgainst(deps map[string][8]byte) error {
+	pfs, err := r.AutolibFingerprints()
+	if err == ErrNoFingerprint {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, pf := range pfs {
+		fp, ok := deps[pf.Pkg]
+		if !ok {
+			continue
+		}
+		if fp != pf.Fingerprint {
+			return fmt.Errorf("fingerprint mismatch for package %q: stale object file", pf.Pkg)
+		}
+	}
+	return nil
+}
+
 func (r *Reader) Pkglist() []string {
 	n := (r.h.Offsets[BlkPkgIdx+1] - r.h.Offsets[BlkPkgIdx]) / stringRefSize
 	s := make([]string, n)
@@ -596,6 +978,77 @@ func (r *Reader) Auxs2(i int) []Aux2 {
 	return (*[1 << 20]Aux2)(unsafe.Pointer(&r.b[off]))[:n:n]
 }
 
+// symIdx resolves a SymRef to the combined defined-symbol index used
+// by Data, Reloc2, Aux2, etc. (SymbolDefs followed by NonPkgDefs), or
+// -1 if s does not name a symbol defined in this object file.
+func (r *Reader) symIdx(s SymRef) int {
+	switch s.PkgIdx {
+	case PkgIdxSelf:
+		if k := int(s.SymIdx); k < r.NSym() {
+			return k
+		}
+	case PkgIdxNone:
+		if k := int(s.SymIdx); k < r.NNonpkgdef() {
+			return r.NSym() + k
+		}
+	}
+	return -1
+}
+
+// AuxPayload returns the fixed-width aux-specific header stored in
+// the Data of the symbol referenced by the j-th aux symbol of the
+// i-th symbol. Its meaning depends on Aux2(i, j).Type(); see
+// CsectAux, FileAux, FunctionBoundsAux, BlockAux, and
+// ExceptionTableAux. It returns nil for aux types that carry no such
+// header, and panics if the referenced symbol is not defined in this
+// object file.
+func (r *Reader) AuxPayload(i, j int) []byte {
+	a := r.Aux2(i, j)
+	n := auxPayloadSize(a.Type())
+	if n == 0 {
+		return nil
+	}
+	k := r.symIdx(a.Sym())
+	if k < 0 {
+		panic("AuxPayload: symbol not defined in this object file")
+	}
+	data := r.Data(k)
+	if len(data) < n {
+		panic("AuxPayload: symbol data too short for aux payload")
+	}
+	return data[:n:n]
+}
+
+// CsectAux2 returns the CsectAux payload referenced by the j-th aux
+// symbol of the i-th symbol.
+func (r *Reader) CsectAux2(i, j int) *CsectAux2 {
+	return (*CsectAux2)(unsafe.Pointer(&r.AuxPayload(i, j)[0]))
+}
+
+// FileAux2 returns the FileAux payload referenced by the j-th aux
+// symbol of the i-th symbol.
+func (r *Reader) FileAux2(i, j int) *FileAux2 {
+	return (*FileAux2)(unsafe.Pointer(&r.AuxPayload(i, j)[0]))
+}
+
+// FunctionBoundsAux2 returns the FunctionBoundsAux payload referenced
+// by the j-th aux symbol of the i-th symbol.
+func (r *Reader) FunctionBoundsAux2(i, j int) *FunctionBoundsAux2 {
+	return (*FunctionBoundsAux2)(unsafe.Pointer(&r.AuxPayload(i, j)[0]))
+}
+
+// BlockAux2 returns the BlockAux payload referenced by the j-th aux
+// symbol of the i-th symbol.
+func (r *Reader) BlockAux2(i, j int) *BlockAux2 {
+	return (*BlockAux2)(unsafe.Pointer(&r.AuxPayload(i, j)[0]))
+}
+
+// ExceptionTableAux2 returns the ExceptionTableAux payload referenced
+// by the j-th aux symbol of the i-th symbol.
+func (r *Reader) ExceptionTableAux2(i, j int) *ExceptionTableAux2 {
+	return (*ExceptionTableAux2)(unsafe.Pointer(&r.AuxPayload(i, j)[0]))
+}
+
 // DataOff returns the offset of the i-th symbol's data.
 func (r *Reader) DataOff(i int) uint32 {
 	dataIdxOff := r.h.Offsets[BlkDataIdx] + uint32(i*4)
@@ -0,0 +1,433 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goobj2
+
+import (
+	"cmd/internal/bio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// buildTestObj writes a minimal object file with the given magic and
+// Autolib entries, and returns its bytes. Only the header and Autolib
+// block are populated; the remaining blocks are left empty.
+func buildTestObj(t *testing.T, magic string, fp [8]byte, autolib []PkgFingerprint) []byte {
+	t.Helper()
+
+	f, err := ioutil.TempFile("", "goobj2test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := f.Name()
+	f.Close()
+	defer os.Remove(name)
+
+	bw, err := bio.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWriter(bw)
+
+	h := Header{Magic: magic, ObjFileFingerprint: fp}
+	old := magic == oldMagic
+	headerSize := h.Size()
+	if old {
+		headerSize -= len(h.ObjFileFingerprint) // oldMagic header has no fingerprint field
+	}
+	w.Bytes(make([]byte, headerSize)) // reserve space, patched below
+
+	for _, pf := range autolib {
+		w.AddString(pf.Pkg)
+	}
+	h.Offsets[BlkAutolib] = w.Offset()
+	for _, pf := range autolib {
+		if old {
+			w.StringRef(pf.Pkg)
+		} else {
+			w.AddAutolib(pf.Pkg, pf.Fingerprint)
+		}
+	}
+	end := w.Offset()
+	for i := BlkAutolib + 1; i < NBlk; i++ {
+		h.Offsets[i] = end
+	}
+
+	bw.MustSeek(0, io.SeekStart)
+	w.RawString(h.Magic)
+	w.Uint32(h.Flags)
+	if !old {
+		w.Bytes(h.ObjFileFingerprint[:])
+	}
+	for _, x := range h.Offsets {
+		w.Uint32(x)
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// buildSymTestObj writes an object file defining syms, one per
+// element of syms/auxs/data: the i-th symbol has the aux entries
+// auxs[i] (each typically self-referencing SymRef{PkgIdxSelf, i}) and
+// Data data[i]. All symbols are defined in SymbolDefs (PkgIdxSelf);
+// there are no NonPkgDefs, NonPkgRefs, or Relocs. Every other block is
+// left empty.
+func buildSymTestObj(t *testing.T, syms []Sym, auxs [][]Aux, data [][]byte) []byte {
+	t.Helper()
+
+	f, err := ioutil.TempFile("", "goobj2test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := f.Name()
+	f.Close()
+	defer os.Remove(name)
+
+	bw, err := bio.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWriter(bw)
+
+	h := Header{Magic: Magic}
+	w.Bytes(make([]byte, h.Size())) // reserve space, patched below
+
+	for _, s := range syms {
+		w.AddString(s.Name)
+	}
+
+	h.Offsets[BlkAutolib] = w.Offset()
+	h.Offsets[BlkPkgIdx] = w.Offset()
+	h.Offsets[BlkDwarfFile] = w.Offset()
+
+	h.Offsets[BlkSymdef] = w.Offset()
+	for i := range syms {
+		syms[i].Write(w)
+	}
+	h.Offsets[BlkNonpkgdef] = w.Offset()
+	h.Offsets[BlkNonpkgref] = w.Offset()
+
+	h.Offsets[BlkRelocIdx] = w.Offset()
+	for range syms {
+		w.Uint32(0)
+	}
+	w.Uint32(0) // N+1 entries, no relocs anywhere
+
+	h.Offsets[BlkAuxIdx] = w.Offset()
+	var auxIdx uint32
+	for _, a := range auxs {
+		w.Uint32(auxIdx)
+		auxIdx += uint32(len(a))
+	}
+	w.Uint32(auxIdx)
+
+	h.Offsets[BlkDataIdx] = w.Offset()
+	var dataOff uint32
+	for _, d := range data {
+		w.Uint32(dataOff)
+		dataOff += uint32(len(d))
+	}
+	w.Uint32(dataOff)
+
+	h.Offsets[BlkReloc] = w.Offset()
+
+	h.Offsets[BlkAux] = w.Offset()
+	for _, a := range auxs {
+		for _, aux := range a {
+			aux.Write(w)
+		}
+	}
+
+	h.Offsets[BlkData] = w.Offset()
+	for _, d := range data {
+		w.Bytes(d)
+	}
+
+	h.Offsets[BlkPcdata] = w.Offset()
+
+	bw.MustSeek(0, io.SeekStart)
+	h.Write(w)
+	if err := bw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestAuxPayloadRoundTrip writes one symbol per XCOFF aux payload
+// type, each carrying an aux entry that self-references its own Data,
+// and checks that AuxPayload and the *2 accessor types read back what
+// was written. It also exercises AuxPayload's two panic paths.
+func TestAuxPayloadRoundTrip(t *testing.T) {
+	self := func(i int) SymRef { return SymRef{PkgIdxSelf, uint32(i)} }
+
+	csect := CsectAux{Length: 100, Align: 3, SymType: XTY_SD, SMClass: XMC_PR}
+	file := FileAux{Lang: 1, CPU: 2}
+	fb := FunctionBoundsAux{BeginSym: self(0), EndSym: self(1), FrameSiz: 64, TagBits: 7}
+	blk := BlockAux{BeginSym: self(0), EndSym: self(1)}
+	et := ExceptionTableAux{ActionOffset: 10, ResumeOffset: 20}
+
+	syms := []Sym{
+		{Name: "csect0"},
+		{Name: "file0"},
+		{Name: "fb0"},
+		{Name: "blk0"},
+		{Name: "et0"},
+		{Name: "badref"},
+		{Name: "shortdata"},
+	}
+	auxs := [][]Aux{
+		{{Type: AuxCsect, Sym: self(0)}},
+		{{Type: AuxFile, Sym: self(1)}},
+		{{Type: AuxFunctionBounds, Sym: self(2)}},
+		{{Type: AuxBlock, Sym: self(3)}},
+		{{Type: AuxExceptionTable, Sym: self(4)}},
+		{{Type: AuxCsect, Sym: SymRef{PkgIdxNone, 0}}}, // no NonPkgDefs: undefined
+		{{Type: AuxCsect, Sym: self(6)}},
+	}
+	data := [][]byte{
+		encodeCsectAux(csect),
+		encodeFileAux(file),
+		encodeFunctionBoundsAux(fb),
+		encodeBlockAux(blk),
+		encodeExceptionTableAux(et),
+		nil,
+		{1, 2, 3}, // shorter than CsectAuxSize
+	}
+
+	r := NewReaderFromBytes(buildSymTestObj(t, syms, auxs, data), true)
+	if r == nil {
+		t.Fatal("NewReaderFromBytes failed")
+	}
+
+	if got := r.CsectAux2(0, 0); got.Length() != csect.Length || got.Align() != csect.Align ||
+		got.SymType() != csect.SymType || got.SMClass() != csect.SMClass {
+		t.Errorf("CsectAux2 = %+v, want %+v", got, csect)
+	}
+	if got := r.FileAux2(1, 0); got.Lang() != file.Lang || got.CPU() != file.CPU {
+		t.Errorf("FileAux2 = %+v, want %+v", got, file)
+	}
+	if got := r.FunctionBoundsAux2(2, 0); got.BeginSym() != fb.BeginSym || got.EndSym() != fb.EndSym ||
+		got.FrameSiz() != fb.FrameSiz || got.TagBits() != fb.TagBits {
+		t.Errorf("FunctionBoundsAux2 = %+v, want %+v", got, fb)
+	}
+	if got := r.BlockAux2(3, 0); got.BeginSym() != blk.BeginSym || got.EndSym() != blk.EndSym {
+		t.Errorf("BlockAux2 = %+v, want %+v", got, blk)
+	}
+	if got := r.ExceptionTableAux2(4, 0); got.ActionOffset() != et.ActionOffset || got.ResumeOffset() != et.ResumeOffset {
+		t.Errorf("ExceptionTableAux2 = %+v, want %+v", got, et)
+	}
+
+	mustPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: want panic, got none", name)
+			}
+		}()
+		f()
+	}
+	mustPanic("AuxPayload with undefined symbol", func() { r.AuxPayload(5, 0) })
+	mustPanic("AuxPayload with short data", func() { r.AuxPayload(6, 0) })
+}
+
+// TestSymCsectRoundTrip checks that Sym2's SMClass and CsectParent
+// accessors read back what Sym.SetCsect/Sym.Write wrote, at the
+// correct byte offsets within SymSize.
+func TestSymCsectRoundTrip(t *testing.T) {
+	parent := SymRef{PkgIdxSelf, 0}
+	syms := []Sym{
+		{Name: "csect0", ABI: 1, Type: 2, Flag: SymFlagDupok, Siz: 8, Align: 8},
+		{Name: "member0", ABI: 0, Type: 3, Flag: 0, Siz: 4, Align: 4},
+	}
+	syms[1].SetCsect(parent, XMC_RW)
+
+	r := NewReaderFromBytes(buildSymTestObj(t, syms, make([][]Aux, len(syms)), make([][]byte, len(syms))), true)
+	if r == nil {
+		t.Fatal("NewReaderFromBytes failed")
+	}
+
+	s0 := r.Sym2(0)
+	if s0.Name(r) != "csect0" || s0.ABI() != 1 || s0.Siz() != 8 || s0.Align() != 8 {
+		t.Errorf("Sym2(0) = %+v, want csect0 fields unaffected by csect additions", s0)
+	}
+	if s0.SMClass() != 0 || s0.CsectParent() != (SymRef{}) {
+		t.Errorf("Sym2(0) SMClass/CsectParent = %d/%+v, want zero value (not set)", s0.SMClass(), s0.CsectParent())
+	}
+
+	s1 := r.Sym2(1)
+	if s1.Name(r) != "member0" || s1.Siz() != 4 || s1.Align() != 4 {
+		t.Errorf("Sym2(1) = %+v, want member0 fields unaffected by csect additions", s1)
+	}
+	if s1.SMClass() != XMC_RW {
+		t.Errorf("Sym2(1).SMClass() = %d, want %d", s1.SMClass(), XMC_RW)
+	}
+	if s1.CsectParent() != parent {
+		t.Errorf("Sym2(1).CsectParent() = %+v, want %+v", s1.CsectParent(), parent)
+	}
+}
+
+// encodeCsectAux, encodeFileAux, encodeFunctionBoundsAux, encodeBlockAux,
+// and encodeExceptionTableAux encode a payload exactly as its Write
+// method would, for use as test symbol Data; there is no Writer
+// variant backed by an in-memory buffer, so these mirror the on-disk
+// layout directly with encoding/binary.
+func encodeCsectAux(a CsectAux) []byte {
+	b := make([]byte, CsectAuxSize)
+	binary.LittleEndian.PutUint64(b, a.Length)
+	b[8], b[9], b[10] = a.Align, a.SymType, a.SMClass
+	return b
+}
+
+func encodeFileAux(a FileAux) []byte {
+	return []byte{a.Lang, a.CPU}
+}
+
+func encodeSymRef(s SymRef) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint32(b, s.PkgIdx)
+	binary.LittleEndian.PutUint32(b[4:], s.SymIdx)
+	return b
+}
+
+func encodeFunctionBoundsAux(a FunctionBoundsAux) []byte {
+	b := make([]byte, FunctionBoundsAuxSize)
+	copy(b, encodeSymRef(a.BeginSym))
+	copy(b[8:], encodeSymRef(a.EndSym))
+	binary.LittleEndian.PutUint32(b[16:], a.FrameSiz)
+	binary.LittleEndian.PutUint16(b[20:], a.TagBits)
+	return b
+}
+
+func encodeBlockAux(a BlockAux) []byte {
+	b := make([]byte, BlockAuxSize)
+	copy(b, encodeSymRef(a.BeginSym))
+	copy(b[8:], encodeSymRef(a.EndSym))
+	return b
+}
+
+func encodeExceptionTableAux(a ExceptionTableAux) []byte {
+	b := make([]byte, ExceptionTableAuxSize)
+	binary.LittleEndian.PutUint32(b, a.ActionOffset)
+	binary.LittleEndian.PutUint32(b[4:], a.ResumeOffset)
+	return b
+}
+
+func TestAutolibRoundTrip(t *testing.T) {
+	fp := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	want := []PkgFingerprint{
+		{Pkg: "fmt", Fingerprint: [8]byte{1, 1, 1, 1, 1, 1, 1, 1}},
+		{Pkg: "internal/reflectlite", Fingerprint: [8]byte{2, 2, 2, 2, 2, 2, 2, 2}},
+	}
+
+	r := NewReaderFromBytes(buildTestObj(t, Magic, fp, want), true)
+	if r == nil {
+		t.Fatal("NewReaderFromBytes failed")
+	}
+	if r.h.ObjFileFingerprint != fp {
+		t.Errorf("ObjFileFingerprint = %v, want %v", r.h.ObjFileFingerprint, fp)
+	}
+
+	wantNames := []string{"fmt", "internal/reflectlite"}
+	if got := r.Autolib(); !stringsEqual(got, wantNames) {
+		t.Errorf("Autolib() = %v, want %v", got, wantNames)
+	}
+
+	got, err := r.AutolibFingerprints()
+	if err != nil {
+		t.Fatalf("AutolibFingerprints: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AutolibFingerprints() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("AutolibFingerprints()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	deps := map[string][8]byte{"fmt": want[0].Fingerprint, "internal/reflectlite": want[1].Fingerprint}
+	if err := r.VerifyAgainst(deps); err != nil {
+		t.Errorf("VerifyAgainst with matching deps: %v", err)
+	}
+
+	deps["fmt"] = [8]byte{9, 9, 9, 9, 9, 9, 9, 9}
+	if err := r.VerifyAgainst(deps); err == nil {
+		t.Error("VerifyAgainst with stale dep: want error, got nil")
+	}
+}
+
+// TestAutolibOldFormat checks that an oldMagic object, whose Autolib
+// entries are bare string refs (no per-package fingerprint), is still
+// parsed at the narrower stride rather than the current autolibSize.
+// Using the wrong stride here would misparse every entry after the
+// first.
+func TestAutolibOldFormat(t *testing.T) {
+	want := []PkgFingerprint{
+		{Pkg: "fmt"},
+		{Pkg: "internal/reflectlite"},
+		{Pkg: "runtime"},
+	}
+
+	r := NewReaderFromBytes(buildTestObj(t, oldMagic, [8]byte{}, want), true)
+	if r == nil {
+		t.Fatal("NewReaderFromBytes failed")
+	}
+	if !r.h.old {
+		t.Fatal("Header.old = false for an oldMagic object")
+	}
+
+	wantNames := []string{"fmt", "internal/reflectlite", "runtime"}
+	if got := r.Autolib(); !stringsEqual(got, wantNames) {
+		t.Errorf("Autolib() = %v, want %v", got, wantNames)
+	}
+
+	if _, err := r.AutolibFingerprints(); err != ErrNoFingerprint {
+		t.Errorf("AutolibFingerprints() on an oldMagic object: err = %v, want ErrNoFingerprint", err)
+	}
+	// An old-format object has nothing to verify; a bootstrap toolchain
+	// that still produces such objects must not be rejected by this check.
+	if err := r.VerifyAgainst(map[string][8]byte{"fmt": {}}); err != nil {
+		t.Errorf("VerifyAgainst() on an oldMagic object: %v, want nil (nothing to verify)", err)
+	}
+}
+
+func TestComputeFingerprint(t *testing.T) {
+	a := []Sym{{Name: "main.f", ABI: 0, Flag: SymFlagLeaf, Siz: 10}}
+	b := []Sym{{Name: "main.f", ABI: 0, Flag: SymFlagLeaf, Siz: 99, Align: 8}}
+	if ComputeFingerprint(a) != ComputeFingerprint(b) {
+		t.Error("ComputeFingerprint changed with Siz/Align, want stable across reproducible recompiles")
+	}
+
+	c := []Sym{{Name: "main.f", ABI: 1, Flag: SymFlagLeaf, Siz: 10}}
+	if ComputeFingerprint(a) == ComputeFingerprint(c) {
+		t.Error("ComputeFingerprint unchanged when ABI differs")
+	}
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}